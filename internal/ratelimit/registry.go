@@ -0,0 +1,83 @@
+// Package ratelimit 提供按仓库（registry host）隔离的简单令牌桶限流器，
+// 用于避免并发处理大量镜像时对单个仓库造成过大压力。
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Bucket 是一个最朴素的令牌桶：固定容量，固定速率匀速补充。
+// 对镜像传输这种场景而言，这比严格的漏桶/令牌桶库更容易推理，
+// 也不需要额外依赖。
+type Bucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	rate     float64 // 每秒补充的令牌数
+	last     time.Time
+}
+
+func newBucket(capacity, ratePerSecond float64) *Bucket {
+	return &Bucket{
+		tokens:   capacity,
+		capacity: capacity,
+		rate:     ratePerSecond,
+		last:     time.Now(),
+	}
+}
+
+// Wait 阻塞直到令牌桶中有至少一个可用令牌，然后消耗它。
+func (b *Bucket) Wait() {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		elapsed := now.Sub(b.last).Seconds()
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return
+		}
+		deficit := 1 - b.tokens
+		b.mu.Unlock()
+		time.Sleep(time.Duration(deficit/b.rate*float64(time.Second)) + time.Millisecond)
+	}
+}
+
+// RegistryLimiter 为每个 registry host 维护独立的令牌桶，
+// 使得对一个仓库的限流不会影响对其他仓库的并发请求。
+type RegistryLimiter struct {
+	mu       sync.Mutex
+	buckets  map[string]*Bucket
+	capacity float64
+	rate     float64
+}
+
+// NewRegistryLimiter 创建一个按 registry host 分桶的限流器。
+// capacity 是单个仓库允许的突发请求数，ratePerSecond 是其稳态速率。
+func NewRegistryLimiter(capacity, ratePerSecond float64) *RegistryLimiter {
+	return &RegistryLimiter{
+		buckets:  make(map[string]*Bucket),
+		capacity: capacity,
+		rate:     ratePerSecond,
+	}
+}
+
+// Wait 阻塞直到 host 对应的令牌桶允许放行一次请求。
+func (l *RegistryLimiter) Wait(host string) {
+	l.mu.Lock()
+	bucket, ok := l.buckets[host]
+	if !ok {
+		bucket = newBucket(l.capacity, l.rate)
+		l.buckets[host] = bucket
+	}
+	l.mu.Unlock()
+
+	bucket.Wait()
+}
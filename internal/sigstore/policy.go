@@ -0,0 +1,73 @@
+// Package sigstore 校验镜像的 cosign/sigstore 签名，并提供把签名制品
+// （sha256-<digest>.sig 标签约定）随镜像一起复制到目标仓库的能力。
+package sigstore
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Rule 描述了某个 registry/仓库前缀下允许信任的签名来源。
+// PublicKeys 和 FulcioIssuers 任一满足即视为通过验证。
+type Rule struct {
+	// PublicKeys 是 cosign 公钥（PEM）文件路径，用于校验传统的 keyed 签名。
+	PublicKeys []string `yaml:"publicKeys"`
+	// FulcioIssuers 是允许的 Fulcio OIDC issuer，用于校验 keyless 签名。
+	FulcioIssuers []string `yaml:"fulcioIssuers"`
+}
+
+// Policy 按 registry/仓库前缀索引验证规则。
+type Policy struct {
+	Registries map[string]Rule `yaml:"registries"`
+}
+
+// LoadPolicy 从 YAML 文件加载验证策略，格式为：
+//
+//	registries:
+//	  my-registry.com/prod/:
+//	    publicKeys:
+//	      - /etc/image-trans-cli/cosign.pub
+//	  my-registry.com/staging/:
+//	    fulcioIssuers:
+//	      - https://token.actions.githubusercontent.com
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading signature policy %s: %w", path, err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("parsing signature policy %s: %w", path, err)
+	}
+	return &policy, nil
+}
+
+// For 返回匹配 image 的规则，取前缀最长的匹配项（更具体的前缀优先）。
+// 没有任何前缀匹配时返回 nil，调用方应将其视为“不受信任”。
+func (p *Policy) For(image string) *Rule {
+	if p == nil {
+		return nil
+	}
+
+	var bestPrefix string
+	var bestRule Rule
+	matched := false
+
+	for prefix, rule := range p.Registries {
+		if !strings.HasPrefix(image, prefix) {
+			continue
+		}
+		if !matched || len(prefix) > len(bestPrefix) {
+			bestPrefix, bestRule, matched = prefix, rule, true
+		}
+	}
+
+	if !matched {
+		return nil
+	}
+	return &bestRule
+}
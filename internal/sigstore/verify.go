@@ -0,0 +1,173 @@
+package sigstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	cosignsignature "github.com/sigstore/cosign/v2/pkg/signature"
+
+	"github.com/Firstnsnd/image-trans-cli/internal/transfer"
+)
+
+// Verify 校验 image 的 cosign 签名是否满足 rule：
+//   - 对每一个 rule.PublicKeys 尝试 keyed 验证，任意一个通过即算成功；
+//   - 否则，如果 rule.FulcioIssuers 非空，尝试 keyless 验证，要求签名证书
+//     的 issuer 出现在该列表中。
+//
+// rule 为 nil（没有任何前缀匹配该镜像）会被当作“不受信任”直接拒绝，
+// 这样未在策略文件中列出的仓库默认是不允许镜像的，符合安全默认值的要求。
+//
+// auth 是该镜像所在 registry 的凭据/TLS 配置（与 buildAuthConfig 喂给
+// transfer 后端的是同一份），用于拉取签名制品本身；为 nil 表示匿名访问。
+func Verify(ctx context.Context, image string, rule *Rule, auth *transfer.Auth) error {
+	if rule == nil {
+		return fmt.Errorf("no signature policy matches %q; refusing to mirror an unconfigured registry", image)
+	}
+	if len(rule.PublicKeys) == 0 && len(rule.FulcioIssuers) == 0 {
+		return fmt.Errorf("signature policy matching %q has no trusted public keys or Fulcio issuers configured", image)
+	}
+
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return fmt.Errorf("parsing %q: %w", image, err)
+	}
+
+	registryClientOpts := []ociremote.Option{ociremote.WithRemoteOptions(remoteOptionsFor(ctx, auth)...)}
+
+	var lastErr error
+
+	for _, keyPath := range rule.PublicKeys {
+		verifier, err := cosignsignature.LoadPublicKey(ctx, keyPath)
+		if err != nil {
+			lastErr = fmt.Errorf("loading public key %s: %w", keyPath, err)
+			continue
+		}
+
+		_, _, err = cosign.VerifyImageSignatures(ctx, ref, &cosign.CheckOpts{
+			SigVerifier: verifier,
+			// No Rekor/TUF root is wired in yet, so we can't check transparency
+			// log inclusion; trust is anchored solely in the configured public
+			// key instead.
+			IgnoreTlog:         true,
+			RegistryClientOpts: registryClientOpts,
+		})
+		if err == nil {
+			return nil
+		}
+		lastErr = fmt.Errorf("verifying against public key %s: %w", keyPath, err)
+	}
+
+	if len(rule.FulcioIssuers) > 0 {
+		identities := make([]cosign.Identity, 0, len(rule.FulcioIssuers))
+		for _, issuer := range rule.FulcioIssuers {
+			identities = append(identities, cosign.Identity{Issuer: issuer, SubjectRegExp: ".*"})
+		}
+
+		rootCerts, err := fulcio.GetRoots()
+		if err != nil {
+			lastErr = fmt.Errorf("loading Fulcio root certificates: %w", err)
+		} else {
+			intermediateCerts, _ := fulcio.GetIntermediates()
+
+			_, _, err := cosign.VerifyImageSignatures(ctx, ref, &cosign.CheckOpts{
+				Identities:        identities,
+				RootCerts:         rootCerts,
+				IntermediateCerts: intermediateCerts,
+				// Same as above: without a Rekor root we can't verify tlog
+				// inclusion, so trust is anchored in the Fulcio cert chain
+				// plus the configured issuer allowlist only.
+				IgnoreTlog:         true,
+				RegistryClientOpts: registryClientOpts,
+			})
+			if err == nil {
+				return nil
+			}
+			lastErr = fmt.Errorf("verifying keyless signature against Fulcio issuers %v: %w", rule.FulcioIssuers, err)
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no usable verification method produced a result")
+	}
+	return fmt.Errorf("no trusted signature found for %q: %w", image, lastErr)
+}
+
+// remoteOptionsFor 把 transfer.Auth 翻译成拉取签名制品所需的 go-containerregistry
+// remote.Option，和 containers_image.go 里 systemContextFor 对 SystemContext
+// 做的事情是一回事，只是目标类型不同：这里签名制品是通过
+// go-containerregistry（cosign 的底层库）而不是 containers/image 拉取的。
+func remoteOptionsFor(ctx context.Context, auth *transfer.Auth) []remote.Option {
+	opts := []remote.Option{remote.WithContext(ctx)}
+	if auth == nil {
+		return opts
+	}
+
+	if auth.IdentityToken != "" {
+		opts = append(opts, remote.WithAuth(&authn.Bearer{Token: auth.IdentityToken}))
+	} else if auth.Username != "" {
+		opts = append(opts, remote.WithAuth(&authn.Basic{Username: auth.Username, Password: auth.Password}))
+	}
+
+	if auth.TLSVerify != nil || auth.CAFile != "" {
+		tlsConfig := &tls.Config{}
+		if auth.TLSVerify != nil && !*auth.TLSVerify {
+			tlsConfig.InsecureSkipVerify = true
+		}
+		if auth.CAFile != "" {
+			if pool, err := loadCAFile(auth.CAFile); err == nil {
+				tlsConfig.RootCAs = pool
+			}
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		opts = append(opts, remote.WithTransport(transport))
+	}
+
+	return opts
+}
+
+func loadCAFile(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// SignatureTag 返回 digest 对应的 cosign 签名制品标签，遵循
+// "sha256-<hex digest>.sig" 的约定（cosign 对其他摘要算法也是同样的
+// "<algorithm>-<hex>.sig" 规则）。
+func SignatureTag(digest string) (string, error) {
+	algo, hex, ok := splitDigest(digest)
+	if !ok {
+		return "", fmt.Errorf("invalid digest %q, expected \"algorithm:hex\"", digest)
+	}
+	if algo == "sha256" && len(hex) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid sha256 digest %q", digest)
+	}
+	return fmt.Sprintf("%s-%s.sig", algo, hex), nil
+}
+
+func splitDigest(digest string) (algo, hex string, ok bool) {
+	for i := 0; i < len(digest); i++ {
+		if digest[i] == ':' {
+			return digest[:i], digest[i+1:], true
+		}
+	}
+	return "", "", false
+}
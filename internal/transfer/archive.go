@@ -0,0 +1,25 @@
+package transfer
+
+import "strings"
+
+// archiveTransportPrefixes 列出了指向本地文件/目录而非远程仓库的
+// containers/image 传输方式。
+var archiveTransportPrefixes = []string{
+	"oci:",
+	"oci-archive:",
+	"docker-archive:",
+	"dir:",
+}
+
+// IsArchiveReference 判断 ref 是否使用了本地（非仓库）传输方式之一：
+// OCI layout 目录、OCI/docker 归档 tar 包，或是一个存放解包层的普通目录。
+// 空气隔离（air-gapped）传输就是通过这些方式，让 source/target
+// 指向磁盘上的文件而不是某个仓库。
+func IsArchiveReference(ref string) bool {
+	for _, prefix := range archiveTransportPrefixes {
+		if strings.HasPrefix(ref, prefix) {
+			return true
+		}
+	}
+	return false
+}
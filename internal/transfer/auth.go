@@ -0,0 +1,31 @@
+package transfer
+
+// Auth 保存单个 registry host 的认证与 TLS 配置，对应 config.yaml 中
+// registries.<host> 的结构，以及 types.DockerAuthConfig 所需的字段。
+type Auth struct {
+	Username      string
+	Password      string
+	IdentityToken string
+	TLSVerify     *bool // nil 表示使用默认值（验证）
+	CAFile        string
+}
+
+// AuthConfig 按 registry host 索引认证信息。"*" 是通配符，
+// 用于 --creds 这种对所有仓库生效的命令行覆盖。
+type AuthConfig map[string]Auth
+
+// For 返回 host 对应的认证信息；如果没有为该 host 配置认证，
+// 但存在通配符覆盖（"*"，通常来自 --creds），则回退到通配符。
+// 两者都没有时返回 nil，表示匿名访问。
+func (c AuthConfig) For(host string) *Auth {
+	if c == nil {
+		return nil
+	}
+	if auth, ok := c[host]; ok {
+		return &auth
+	}
+	if auth, ok := c["*"]; ok {
+		return &auth
+	}
+	return nil
+}
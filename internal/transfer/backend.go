@@ -0,0 +1,57 @@
+// Package transfer 封装了镜像拉取/打标/推送的具体实现，
+// 允许在 docker CLI 和 containers/image 库之间切换后端。
+package transfer
+
+// Backend 定义了镜像传输所需的最小操作集合。
+// docker 子命令和 containers/image 库两种实现都满足该接口，
+// 上层的 processImages 只依赖 Backend，不关心具体实现。
+type Backend interface {
+	// Pull 拉取 source 指向的镜像到本地（或后端自身的中转存储）。
+	Pull(source string) error
+	// Tag 将已拉取的 source 镜像标记为 target。
+	Tag(source, target string) error
+	// Push 将 target 镜像推送到目标仓库。platforms 用于多架构 manifest
+	// list/index 源：选择要拷贝的子镜像。不支持平台选择的后端
+	// （例如 DockerBackend）会忽略非默认的 platforms 并回退到宿主机架构。
+	Push(target string, platforms PlatformSelection) (PushResult, error)
+}
+
+// DigestBackend 是一个可选能力：能够返回已 Pull 过的镜像的 manifest
+// digest。只有 ContainersImageBackend 实现它；--copy-signatures 需要
+// 用这个 digest 按 cosign 的约定定位签名制品标签。
+type DigestBackend interface {
+	Backend
+	Digest(source string) (string, error)
+}
+
+// Name 标识了受支持的后端类型，对应 --backend 命令行参数的取值。
+type Name string
+
+const (
+	// BackendDocker 通过本地 docker CLI 执行 pull/tag/push，依赖 Docker daemon。
+	BackendDocker Name = "docker"
+	// BackendContainersImage 使用 containers/image 库直接与镜像仓库交互，无需 Docker daemon。
+	BackendContainersImage Name = "containers-image"
+)
+
+// New 根据名称构造对应的 Backend 实现，auth 中保存的凭据
+// 会在 Pull/Push 时按目标 registry host 自动匹配使用。
+func New(name Name, verbose bool, auth AuthConfig) (Backend, error) {
+	switch name {
+	case "", BackendDocker:
+		return NewDockerBackend(verbose, auth), nil
+	case BackendContainersImage:
+		return NewContainersImageBackend(verbose, auth)
+	default:
+		return nil, &UnsupportedBackendError{Name: string(name)}
+	}
+}
+
+// UnsupportedBackendError 在 --backend 取值无法识别时返回。
+type UnsupportedBackendError struct {
+	Name string
+}
+
+func (e *UnsupportedBackendError) Error() string {
+	return "unsupported backend: " + e.Name + " (expected \"docker\" or \"containers-image\")"
+}
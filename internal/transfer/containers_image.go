@@ -0,0 +1,341 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+)
+
+// pulledImage 记录一次 Pull 解析出的源引用及其认证上下文，
+// 在 Tag 时被搬到 staged（以 target 为 key），Push 时取出消费。
+// 跨调用的状态只保存在并发安全的 sync.Map（pulled/staged）中，
+// 因此 ContainersImageBackend 可以被 worker pool 并发复用。
+type pulledImage struct {
+	ref types.ImageReference
+	sys *types.SystemContext
+}
+
+// ContainersImageBackend 使用 containers/image 库直接与镜像仓库交互，
+// 不再依赖本地 docker daemon，适合 CI / 无 daemon 的环境。
+//
+// Pull/Tag/Push 三个方法共同对应 copy.Image 的一次调用：Pull 记录源引用，
+// Tag 把它搬到以 target 为 key 的 staged 表，Push 时取出并真正执行拷贝，
+// 这样可以保留和 DockerBackend 相同的调用顺序，便于 processImages
+// 无差别地驱动两种后端，同时允许多个镜像并发走完整个流水线。
+type ContainersImageBackend struct {
+	verbose bool
+	auth    AuthConfig
+
+	ctx           context.Context
+	policyContext *signature.PolicyContext
+
+	pulled sync.Map // source string -> *pulledImage
+	staged sync.Map // target string -> *pulledImage
+}
+
+// NewContainersImageBackend 构造一个基于 containers/image 的 Backend，
+// 策略文件固定读取 policy.json（与 skopeo 默认行为一致）。
+func NewContainersImageBackend(verbose bool, auth AuthConfig) (*ContainersImageBackend, error) {
+	policy, err := signature.DefaultPolicy(nil)
+	if err != nil {
+		return nil, fmt.Errorf("loading signature policy: %w", err)
+	}
+
+	policyContext, err := signature.NewPolicyContext(policy)
+	if err != nil {
+		return nil, fmt.Errorf("creating policy context: %w", err)
+	}
+
+	return &ContainersImageBackend{
+		verbose:       verbose,
+		auth:          auth,
+		ctx:           context.Background(),
+		policyContext: policyContext,
+	}, nil
+}
+
+func (b *ContainersImageBackend) Pull(source string) error {
+	ref, err := alltransports.ParseImageName(dockerTransportRef(source))
+	if err != nil {
+		return fmt.Errorf("parsing source reference %q: %w", source, err)
+	}
+	if b.verbose {
+		fmt.Printf("  Resolved source reference: %s\n", transportString(ref))
+	}
+	sys, err := systemContextFor(b.auth.For(RegistryHost(source)))
+	if err != nil {
+		return fmt.Errorf("building system context for %q: %w", source, err)
+	}
+	b.pulled.Store(source, &pulledImage{
+		ref: ref,
+		sys: sys,
+	})
+	return nil
+}
+
+func (b *ContainersImageBackend) Tag(source, target string) error {
+	if b.verbose {
+		fmt.Printf("  Target reference: %s\n", target)
+	}
+	if target == "" {
+		return fmt.Errorf("target image reference must not be empty")
+	}
+
+	pulled, ok := b.pulled.Load(source)
+	if !ok {
+		return fmt.Errorf("no pulled image found for source %q", source)
+	}
+	b.staged.Store(target, pulled)
+	return nil
+}
+
+// Digest 返回 source 的 manifest digest（"sha256:..."），source 必须已经
+// 调用过 Pull。用于 --copy-signatures 按 cosign 的 sha256-<digest>.sig
+// 标签约定定位签名制品。
+func (b *ContainersImageBackend) Digest(source string) (string, error) {
+	v, ok := b.pulled.Load(source)
+	if !ok {
+		return "", fmt.Errorf("no pulled image found for source %q", source)
+	}
+	pulled := v.(*pulledImage)
+
+	src, err := pulled.ref.NewImageSource(b.ctx, pulled.sys)
+	if err != nil {
+		return "", fmt.Errorf("opening source image: %w", err)
+	}
+	defer src.Close()
+
+	rawManifest, _, err := src.GetManifest(b.ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("reading manifest: %w", err)
+	}
+
+	d, err := manifest.Digest(rawManifest)
+	if err != nil {
+		return "", fmt.Errorf("computing digest: %w", err)
+	}
+	return d.String(), nil
+}
+
+func (b *ContainersImageBackend) Push(target string, platforms PlatformSelection) (PushResult, error) {
+	staged, ok := b.staged.LoadAndDelete(target)
+	if !ok {
+		return PushResult{}, fmt.Errorf("no staged image found for target %q", target)
+	}
+	src := staged.(*pulledImage)
+
+	destRef, err := alltransports.ParseImageName(dockerTransportRef(target))
+	if err != nil {
+		return PushResult{}, fmt.Errorf("parsing target reference %q: %w", target, err)
+	}
+
+	if b.verbose {
+		fmt.Printf("  Copying %s -> %s\n", transportString(src.ref), transportString(destRef))
+	}
+
+	destSys, err := systemContextFor(b.auth.For(RegistryHost(target)))
+	if err != nil {
+		return PushResult{}, fmt.Errorf("building system context for %q: %w", target, err)
+	}
+
+	opts := &copy.Options{
+		SourceCtx:      src.sys,
+		DestinationCtx: destSys,
+	}
+
+	var pushResult PushResult
+	switch {
+	case platforms.All:
+		opts.ImageListSelection = copy.CopyAllImages
+	case len(platforms.Platforms) > 0:
+		instances, results, err := b.resolveInstances(src, platforms.Platforms)
+		if err != nil {
+			return PushResult{}, fmt.Errorf("resolving requested platforms for %q: %w", target, err)
+		}
+		opts.ImageListSelection = copy.CopySpecificImages
+		opts.Instances = instances
+		pushResult.Platforms = results
+	default:
+		opts.ImageListSelection = copy.CopySystemImage
+	}
+
+	manifestBytes, err := copy.Image(b.ctx, b.policyContext, destRef, src.ref, opts)
+	if err != nil {
+		if len(pushResult.Platforms) > 0 {
+			for i := range pushResult.Platforms {
+				pushResult.Platforms[i].Success = false
+				pushResult.Platforms[i].Error = err
+			}
+		}
+		return pushResult, err
+	}
+
+	pushResult.BytesTransferred = manifestLayersSize(manifestBytes)
+	if d, err := manifest.Digest(manifestBytes); err == nil {
+		pushResult.TargetDigest = d.String()
+	}
+	return pushResult, nil
+}
+
+// manifestLayersSize sums the sizes of the layers listed in a manifest, as a
+// best-effort estimate of the bytes transferred by a Push. It returns 0 for
+// manifest list/index "manifests" (there are no layers on the index itself)
+// rather than erroring, since BytesTransferred is informational only.
+func manifestLayersSize(rawManifest []byte) int64 {
+	mimeType := manifest.GuessMIMEType(rawManifest)
+	if manifest.MIMETypeIsMultiImage(mimeType) {
+		return 0
+	}
+
+	parsed, err := manifest.FromBlob(rawManifest, mimeType)
+	if err != nil {
+		return 0
+	}
+
+	var total int64
+	for _, layer := range parsed.LayerInfos() {
+		total += layer.Size
+	}
+	return total
+}
+
+// resolveInstances 读取源镜像的原始 manifest，如果是一个多架构 manifest
+// list/index，就挑出 wanted 中每个平台对应的子 manifest digest；
+// 未被 manifest list 列出的平台记为失败，这样调用方能按平台报告结果。
+func (b *ContainersImageBackend) resolveInstances(src *pulledImage, wanted []Platform) ([]digest.Digest, []PlatformResult, error) {
+	srcImgSrc, err := src.ref.NewImageSource(b.ctx, src.sys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening source image: %w", err)
+	}
+	defer srcImgSrc.Close()
+
+	rawManifest, mimeType, err := srcImgSrc.GetManifest(b.ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading manifest: %w", err)
+	}
+
+	available := map[Platform]digest.Digest{}
+	switch {
+	case manifest.MIMETypeIsMultiImage(mimeType) && mimeType == manifest.DockerV2ListMediaType:
+		list, err := manifest.Schema2ListFromManifest(rawManifest)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing docker manifest list: %w", err)
+		}
+		for _, m := range list.Manifests {
+			available[Platform{OS: m.Platform.OS, Architecture: m.Platform.Architecture, Variant: m.Platform.Variant}] = m.Digest
+		}
+	case manifest.MIMETypeIsMultiImage(mimeType):
+		index, err := manifest.OCI1IndexFromManifest(rawManifest)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing OCI index: %w", err)
+		}
+		for _, m := range index.Manifests {
+			if m.Platform == nil {
+				continue
+			}
+			available[Platform{OS: m.Platform.OS, Architecture: m.Platform.Architecture, Variant: m.Platform.Variant}] = m.Digest
+		}
+	default:
+		// Single-arch source: its platform is not known without fetching
+		// the image, so each requested platform is reported as unavailable.
+		// The lone image is still copied by copy.Image in Push; the failed
+		// platform results keep that copy from going unreported.
+		results := make([]PlatformResult, len(wanted))
+		for i, p := range wanted {
+			results[i] = PlatformResult{Platform: p.String(), Error: fmt.Errorf("source is not a multi-arch manifest list")}
+		}
+		return nil, results, nil
+	}
+
+	var instances []digest.Digest
+	results := make([]PlatformResult, 0, len(wanted))
+	for _, p := range wanted {
+		d, ok := available[p]
+		if !ok {
+			results = append(results, PlatformResult{Platform: p.String(), Success: false, Error: fmt.Errorf("platform not present in source manifest list")})
+			continue
+		}
+		instances = append(instances, d)
+		results = append(results, PlatformResult{Platform: p.String(), Success: true})
+	}
+
+	return instances, results, nil
+}
+
+// systemContextFor 把一个 registry host 的 Auth 翻译成 containers/image
+// 所需的 SystemContext。auth 为 nil 时返回一个空的 SystemContext（匿名访问）。
+func systemContextFor(auth *Auth) (*types.SystemContext, error) {
+	sys := &types.SystemContext{}
+	if auth == nil {
+		return sys, nil
+	}
+
+	if auth.IdentityToken != "" {
+		sys.DockerAuthConfig = &types.DockerAuthConfig{IdentityToken: auth.IdentityToken}
+	} else if auth.Username != "" {
+		sys.DockerAuthConfig = &types.DockerAuthConfig{
+			Username: auth.Username,
+			Password: auth.Password,
+		}
+	}
+
+	if auth.TLSVerify != nil {
+		sys.DockerInsecureSkipTLSVerify = types.NewOptionalBool(!*auth.TLSVerify)
+	}
+	if auth.CAFile != "" {
+		// DockerCertPath 必须是一个目录：tlsclientconfig.SetupCertificates
+		// 会 os.ReadDir 它并加载里面所有的 *.crt/*.cert/*.key 文件，指向
+		// 一个文件会让 ReadDir 返回 ENOTDIR。把 CAFile 复制到一个临时目录
+		// 里去满足这个约定。
+		certDir, err := stageCertDir(auth.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("staging CA file %q: %w", auth.CAFile, err)
+		}
+		sys.DockerCertPath = certDir
+	}
+
+	return sys, nil
+}
+
+// stageCertDir 把单个 CA 证书文件复制到一个新建的临时目录中，
+// 满足 DockerCertPath 要求目录而非文件的约定。
+func stageCertDir(caFile string) (string, error) {
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp("", "image-trans-cli-ca-")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ca.crt"), data, 0o644); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// dockerTransportRef 在裸镜像名（没有显式 transport 前缀）前补上 docker:// transport，
+// 这样 "registry/repo:tag" 这类 docker CLI 习惯的写法也能被 alltransports 解析。
+func dockerTransportRef(image string) string {
+	if ref, err := alltransports.ParseImageName(image); err == nil && ref != nil {
+		return image
+	}
+	return docker.Transport.Name() + "://" + image
+}
+
+func transportString(ref types.ImageReference) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.Transport().Name() + ":" + ref.StringWithinTransport()
+}
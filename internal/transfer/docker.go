@@ -0,0 +1,130 @@
+package transfer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DockerBackend 通过本地 docker CLI 执行 pull/tag/push，
+// 是迁移到 containers/image 之前的原始实现，作为兼容回退保留。
+// 同一个 DockerBackend 实例会被 worker pool 并发调用，因此 loggedIn
+// 需要加锁保护。
+type DockerBackend struct {
+	verbose bool
+	auth    AuthConfig
+
+	loginMu  sync.Mutex
+	loggedIn map[string]bool
+}
+
+// NewDockerBackend 创建一个基于 docker CLI 的 Backend。
+// 如果 auth 中为某个 registry host 配置了凭据，会在首次访问该仓库前
+// 透明地执行一次 `docker login`，使用户不必提前手动登录。
+func NewDockerBackend(verbose bool, auth AuthConfig) *DockerBackend {
+	return &DockerBackend{
+		verbose:  verbose,
+		auth:     auth,
+		loggedIn: make(map[string]bool),
+	}
+}
+
+func (b *DockerBackend) Pull(source string) error {
+	if IsArchiveReference(source) {
+		return fmt.Errorf("source %q uses a local archive transport, which the docker backend cannot read; use --backend=containers-image", source)
+	}
+	if err := b.ensureLoggedIn(RegistryHost(source)); err != nil {
+		return err
+	}
+	if b.verbose {
+		fmt.Printf("  Pulling source image: %s\n", source)
+	}
+	return b.run("pull", source)
+}
+
+func (b *DockerBackend) Tag(source, target string) error {
+	if IsArchiveReference(target) {
+		return fmt.Errorf("target %q uses a local archive transport, which the docker backend cannot write; use --backend=containers-image", target)
+	}
+	if b.verbose {
+		fmt.Printf("  Tagging image as: %s\n", target)
+	}
+	return b.run("tag", source, target)
+}
+
+func (b *DockerBackend) Push(target string, platforms PlatformSelection) (PushResult, error) {
+	if IsArchiveReference(target) {
+		return PushResult{}, fmt.Errorf("target %q uses a local archive transport, which the docker backend cannot write; use --backend=containers-image", target)
+	}
+	if !platforms.IsDefault() && b.verbose {
+		fmt.Printf("  Warning: docker backend does not support platform selection; pushing host-arch image only\n")
+	}
+
+	if err := b.ensureLoggedIn(RegistryHost(target)); err != nil {
+		return PushResult{}, err
+	}
+	if b.verbose {
+		fmt.Printf("  Pushing image to target repository: %s\n", target)
+	}
+	if err := b.run("push", target); err != nil {
+		return PushResult{}, err
+	}
+	return PushResult{BytesTransferred: b.inspectSize(target)}, nil
+}
+
+// inspectSize returns the local on-disk size of image as reported by
+// `docker image inspect`, used as a best-effort stand-in for bytes
+// transferred. It returns 0 (rather than an error) when inspection fails,
+// since this is informational only and must never fail the push itself.
+func (b *DockerBackend) inspectSize(image string) int64 {
+	out, err := exec.Command("docker", "image", "inspect", image, "--format", "{{.Size}}").Output()
+	if err != nil {
+		return 0
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// ensureLoggedIn 在 host 配置了凭据且尚未登录过的情况下执行一次 docker login。
+// 密码通过 --password-stdin 传入，避免出现在进程参数列表中。
+func (b *DockerBackend) ensureLoggedIn(host string) error {
+	b.loginMu.Lock()
+	defer b.loginMu.Unlock()
+
+	auth := b.auth.For(host)
+	if auth == nil || b.loggedIn[host] {
+		return nil
+	}
+
+	username := auth.Username
+	secret := auth.Password
+	if auth.IdentityToken != "" {
+		secret = auth.IdentityToken
+	}
+	if username == "" && secret == "" {
+		return nil
+	}
+
+	cmd := exec.Command("docker", "login", host, "--username", username, "--password-stdin")
+	cmd.Stdin = strings.NewReader(secret)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker login to %s: %w", host, err)
+	}
+	b.loggedIn[host] = true
+	return nil
+}
+
+func (b *DockerBackend) run(args ...string) error {
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,91 @@
+package transfer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Platform 标识多架构 manifest list/OCI index 中的一个条目，
+// 格式与 docker/podman 命令行一致："os/arch[/variant]"。
+type Platform struct {
+	OS           string
+	Architecture string
+	Variant      string
+}
+
+func (p Platform) String() string {
+	if p.Variant != "" {
+		return fmt.Sprintf("%s/%s/%s", p.OS, p.Architecture, p.Variant)
+	}
+	return fmt.Sprintf("%s/%s", p.OS, p.Architecture)
+}
+
+// ParsePlatform 解析单个 "os/arch" 或 "os/arch/variant" 字符串。
+func ParsePlatform(s string) (Platform, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return Platform{}, fmt.Errorf("invalid platform %q, expected os/arch or os/arch/variant", s)
+	}
+	p := Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+// ParsePlatforms 解析逗号分隔的平台列表，即 --platform 接受的格式。
+func ParsePlatforms(csv string) ([]Platform, error) {
+	fields := strings.Split(csv, ",")
+	platforms := make([]Platform, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		p, err := ParsePlatform(f)
+		if err != nil {
+			return nil, err
+		}
+		platforms = append(platforms, p)
+	}
+	return platforms, nil
+}
+
+// PlatformSelection 描述应该从一个多架构镜像中拷贝哪些子 manifest。
+// 零值表示"默认"：对 manifest list 只拷贝匹配本机架构的那一个实例
+// （即目前的行为），和普通的 `docker pull` 一样。
+type PlatformSelection struct {
+	All       bool
+	Platforms []Platform
+}
+
+// IsDefault 判断 sel 是否没有请求任何显式的平台过滤。
+func (sel PlatformSelection) IsDefault() bool {
+	return !sel.All && len(sel.Platforms) == 0
+}
+
+// PlatformResult 记录从多架构源中拷贝单个平台 manifest 的结果，
+// 挂在 ImageResult 上供调用方按架构报告成功/失败。
+type PlatformResult struct {
+	Platform string
+	Success  bool
+	Error    error
+}
+
+// PushResult 携带一次 Push 的结果。Platforms 仅在请求了显式平台列表
+// （PlatformSelection.Platforms 非空）时由 ContainersImageBackend 填充：
+// 源为 manifest list/index 时按平台记录成功与否，源为单架构镜像时
+// 每个请求的平台都记为失败；All 或默认选择不填充 Platforms。
+type PushResult struct {
+	Platforms []PlatformResult
+
+	// BytesTransferred 是本次推送镜像大小的估计值，供 --output=json 使用。
+	// 后端如果无法低成本地获取这个值（例如 DockerBackend 的
+	// `docker image inspect` 失败时），就留空为 0。
+	BytesTransferred int64
+
+	// TargetDigest 是推送后镜像的 manifest digest，仅当后端能不增加
+	// 额外往返就获取到它时才会填充。DockerBackend 留空；
+	// ContainersImageBackend 从 copy.Image 已经返回的 manifest 字节中获取。
+	TargetDigest string
+}
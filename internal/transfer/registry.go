@@ -0,0 +1,36 @@
+package transfer
+
+import "strings"
+
+// localHost 是本地文件系统传输（oci:/docker-archive:/dir: 等）的 registry
+// host 占位符：这些引用不涉及远程仓库，限流和按仓库认证都没有意义。
+const localHost = "local"
+
+// RegistryHost 从镜像引用中解析出 registry host，用于按仓库限流、
+// 按仓库选择认证信息等场景。
+// 例如 "docker.vaniot.net/nginx:latest" -> "docker.vaniot.net"；
+// 没有点号/端口且不是 "localhost" 的第一段（如 "library/nginx"），以及不含 "/"
+// 的引用（如 "nginx:latest"），都视为 Docker Hub，返回 "docker.io"。
+// oci:/docker-archive:/dir: 等本地传输一律返回 localHost。
+func RegistryHost(image string) string {
+	if IsArchiveReference(image) {
+		return localHost
+	}
+
+	name := image
+	if idx := strings.Index(name, "://"); idx != -1 {
+		name = name[idx+3:]
+	}
+
+	firstSegment := name
+	if idx := strings.Index(name, "/"); idx != -1 {
+		firstSegment = name[:idx]
+	} else {
+		return "docker.io"
+	}
+
+	if strings.Contains(firstSegment, ".") || strings.Contains(firstSegment, ":") || firstSegment == "localhost" {
+		return firstSegment
+	}
+	return "docker.io"
+}
@@ -0,0 +1,30 @@
+package transfer
+
+import "testing"
+
+func TestRegistryHost(t *testing.T) {
+	cases := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{"docker hub shorthand, no slash", "nginx:latest", "docker.io"},
+		{"docker hub shorthand, namespaced", "library/nginx:latest", "docker.io"},
+		{"explicit host with dot", "docker.vaniot.net/nginx:latest", "docker.vaniot.net"},
+		{"explicit host with port", "host:5000/repo:tag", "host:5000"},
+		{"localhost", "localhost:5000/repo:tag", "localhost:5000"},
+		{"docker:// transport prefix", "docker://docker.vaniot.net/nginx:latest", "docker.vaniot.net"},
+		{"digest reference", "registry.io/ns/repo@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcd", "registry.io"},
+		{"oci archive is local", "oci:/backup/layout:nginx-latest", localHost},
+		{"docker-archive is local", "docker-archive:/backup/images.tar:nginx-latest", localHost},
+		{"dir transport is local", "dir:/backup/nginx", localHost},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := RegistryHost(tc.image); got != tc.want {
+				t.Errorf("RegistryHost(%q) = %q, want %q", tc.image, got, tc.want)
+			}
+		})
+	}
+}
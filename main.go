@@ -1,19 +1,77 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"github.com/Firstnsnd/image-trans-cli/internal/ratelimit"
+	"github.com/Firstnsnd/image-trans-cli/internal/sigstore"
+	"github.com/Firstnsnd/image-trans-cli/internal/transfer"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 	"log"
+	"math"
+	"math/rand"
 	"os"
 	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
 	"time"
 )
 
+// exitCodeImagesFailed is returned when config loading and setup succeeded
+// but one or more images failed to transfer, distinct from the generic
+// os.Exit(1) used for config/setup errors so CI pipelines can tell the two
+// apart.
+const exitCodeImagesFailed = 2
+
 // Config 定义了配置文件的结构
 type Config struct {
-	Images []string `yaml:"images"`
-	Target string   `yaml:"target"`
+	Images     []ImageSpec             `yaml:"images"`
+	Target     string                  `yaml:"target"`
+	Registries map[string]RegistryAuth `yaml:"registries"`
+}
+
+// RegistryAuth 描述了单个 registry host 的认证与 TLS 配置。
+// Password/PasswordFile/IdentityToken 互斥，按此优先级取用：
+// IdentityToken > PasswordFile > Password。
+type RegistryAuth struct {
+	Username      string `yaml:"username"`
+	Password      string `yaml:"password"`
+	PasswordFile  string `yaml:"passwordFile"`
+	IdentityToken string `yaml:"identityToken"`
+	TLSVerify     *bool  `yaml:"tlsVerify"`
+	CAFile        string `yaml:"caFile"`
+}
+
+// ImageSpec 描述了 images 列表中的一项。为了兼容旧配置文件，
+// 一项可以只写成普通字符串（等价于只设置 Source），也可以写成
+// 带 source/target/targetTag/platform 字段的对象，参见 UnmarshalYAML。
+type ImageSpec struct {
+	Source    string `yaml:"source"`
+	Target    string `yaml:"target"`
+	TargetTag string `yaml:"targetTag"`
+	Platform  string `yaml:"platform"`
+}
+
+// UnmarshalYAML 让 images 列表中的一项既可以是裸字符串（旧格式），
+// 也可以是带 source/target 等字段的对象（新格式）。
+func (s *ImageSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var source string
+	if err := unmarshal(&source); err == nil {
+		s.Source = source
+		return nil
+	}
+
+	type plain ImageSpec // 避免递归调用 UnmarshalYAML
+	var p plain
+	if err := unmarshal(&p); err != nil {
+		return err
+	}
+	*s = ImageSpec(p)
+	return nil
 }
 
 // ImageResult 定义镜像处理结果
@@ -23,18 +81,47 @@ type ImageResult struct {
 	Success     bool
 	FailStage   string // pull, tag, or push
 	Error       error
+	Platforms   []transfer.PlatformResult // 仅当请求了显式平台列表（--platform 或 --platform-from-config）时填充，见 transfer.PushResult
+
+	Duration         time.Duration // 处理该镜像花费的总时长，供 --output=json/junit 报告使用
+	BytesTransferred int64         // Push 阶段的字节数估计，后端不支持时为 0
+	SourceDigest     string        // 源镜像 manifest digest，仅 DigestBackend 支持时填充
+	TargetDigest     string        // 目标镜像 manifest digest，仅 DigestBackend 支持时填充
 }
 
 const (
-	MaxRetries    = 3 // 最大重试次数
-	RetryInterval = 3 // 重试间隔（秒）
+	MaxRetries = 3 // 最大重试次数
+
+	retryBaseDelay = time.Second      // 退避基准延迟
+	retryFactor    = 2                // 每次重试延迟的增长倍数
+	retryMaxDelay  = 30 * time.Second // 退避延迟上限
+	retryJitter    = 0.2              // 抖动幅度（±20%）
+
+	// registryBurst/registryRatePerSecond 控制对单个 registry host 的
+	// 并发请求突发量和稳态速率，避免并行拉取/推送打爆一个仓库。
+	registryBurst         = 5
+	registryRatePerSecond = 5
 )
 
 func main() {
 	var (
-		configPath string
-		verbose    bool
-		dryRun     bool
+		configPath  string
+		verbose     bool
+		dryRun      bool
+		backendName string
+		parallel    int
+		creds       string
+
+		allPlatforms       bool
+		platformFlag       string
+		platformFromConfig bool
+
+		targetTransport string
+
+		copySignatures       bool
+		verifySignaturesPath string
+
+		outputFormat string
 	)
 
 	rootCmd := &cobra.Command{
@@ -51,8 +138,21 @@ Example usage:
 Configuration file (config.yaml) format:
   images:
     - docker.vaniot.net/nginx:latest
-    - docker.vaniot.net/redis:6
-  target: my-registry.com`,
+    - source: docker.vaniot.net/redis:6
+      targetTag: 6-mirrored
+  target: my-registry.com
+  registries:
+    my-registry.com:
+      username: robot
+      passwordFile: /run/secrets/my-registry-password
+
+Images and the target may also use the oci:, docker-archive:, or dir:
+transports (with --backend=containers-image) to read from or write to a
+local file instead of a registry, e.g. for air-gapped transfers.
+
+Use --output=json or --output=junit for machine-readable results in CI;
+the process exits with status 2 (rather than the usual 1) when every
+image was processed but at least one transfer failed.`,
 		Example: `  # Process images using configuration file
     image-trans-cli -c ./config.yaml
   
@@ -69,7 +169,7 @@ Configuration file (config.yaml) format:
 		},
 
 		Run: func(cmd *cobra.Command, args []string) {
-			if !checkDockerInstalled() {
+			if backendName == string(transfer.BackendDocker) && !checkDockerInstalled() {
 				log.Fatal("Docker is not installed or not available in PATH. Please install Docker and try again.")
 			}
 
@@ -92,8 +192,68 @@ Configuration file (config.yaml) format:
 				log.Fatal("Target repository is not specified in the config file.")
 			}
 
-			results := processImages(config.Images, config.Target, verbose, dryRun)
-			printResults(results, verbose)
+			defaultTarget := config.Target
+			if targetTransport != "" && !transfer.IsArchiveReference(defaultTarget) {
+				defaultTarget = fmt.Sprintf("%s:%s", targetTransport, defaultTarget)
+			}
+
+			auth, err := buildAuthConfig(config.Registries, creds)
+			if err != nil {
+				log.Fatalf("Failed to build registry credentials: %v", err)
+			}
+
+			backend, err := transfer.New(transfer.Name(backendName), verbose, auth)
+			if err != nil {
+				log.Fatalf("Failed to initialize backend: %v", err)
+			}
+
+			if parallel <= 0 {
+				parallel = 1
+			}
+
+			requestedPlatforms, err := transfer.ParsePlatforms(platformFlag)
+			if err != nil {
+				log.Fatalf("Invalid --platform value: %v", err)
+			}
+			platformOpts := platformOptions{
+				all:        allPlatforms,
+				platforms:  requestedPlatforms,
+				fromConfig: platformFromConfig,
+			}
+
+			var policy *sigstore.Policy
+			if verifySignaturesPath != "" {
+				policy, err = sigstore.LoadPolicy(verifySignaturesPath)
+				if err != nil {
+					log.Fatalf("Failed to load signature policy: %v", err)
+				}
+			}
+			sigOpts := signatureOptions{
+				copySignatures: copySignatures,
+				policy:         policy,
+				auth:           auth,
+			}
+
+			results := processImages(config.Images, defaultTarget, backend, platformOpts, sigOpts, verbose, dryRun, parallel)
+
+			switch outputFormat {
+			case "", "text":
+				printResults(results, verbose)
+			case "json":
+				if err := printResultsJSON(results); err != nil {
+					log.Fatalf("Failed to render JSON output: %v", err)
+				}
+			case "junit":
+				if err := printResultsJUnit(results); err != nil {
+					log.Fatalf("Failed to render JUnit output: %v", err)
+				}
+			default:
+				log.Fatalf("Unknown --output value %q (expected \"text\", \"json\", or \"junit\")", outputFormat)
+			}
+
+			if anyFailed(results) {
+				os.Exit(exitCodeImagesFailed)
+			}
 		},
 	}
 
@@ -102,6 +262,16 @@ Configuration file (config.yaml) format:
 	rootCmd.MarkFlagRequired("config")
 	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
 	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview actions without executing them")
+	rootCmd.Flags().StringVar(&backendName, "backend", string(transfer.BackendDocker), "Transfer backend to use: \"docker\" (shells out to the docker CLI) or \"containers-image\" (daemonless, uses containers/image)")
+	rootCmd.Flags().IntVar(&parallel, "parallel", runtime.NumCPU(), "Number of images to process concurrently")
+	rootCmd.Flags().StringVar(&creds, "creds", "", "Credentials (USERNAME:PASSWORD) used as a fallback for any registry not listed under \"registries\" in the config file")
+	rootCmd.Flags().BoolVar(&allPlatforms, "all-platforms", false, "Copy every platform of a multi-arch manifest list/index instead of just the host's architecture")
+	rootCmd.Flags().StringVar(&platformFlag, "platform", "", "Comma-separated list of platforms to copy from a multi-arch source, e.g. linux/amd64,linux/arm64")
+	rootCmd.Flags().BoolVar(&platformFromConfig, "platform-from-config", false, "Use each image's \"platform\" field from the config file to select a single platform from a multi-arch source")
+	rootCmd.Flags().StringVar(&targetTransport, "target-transport", "", "Write to a local archive instead of a registry: \"oci\" writes an OCI image layout directory, \"docker-archive\" writes a docker save-compatible tar. Combines with the config file's \"target\" path")
+	rootCmd.Flags().BoolVar(&copySignatures, "copy-signatures", false, "Also copy the source image's cosign/sigstore signature artifacts to the target registry")
+	rootCmd.Flags().StringVar(&verifySignaturesPath, "verify-signatures", "", "Path to a signature policy YAML file; refuse to push any image whose source signatures don't satisfy it")
+	rootCmd.Flags().StringVar(&outputFormat, "output", "text", "Output format for results: \"text\" (default, human-readable), \"json\" (machine-readable array of results), or \"junit\" (JUnit XML test report for CI)")
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)
@@ -132,92 +302,281 @@ func loadConfig(path string) (*Config, error) {
 	return &config, nil
 }
 
-// processImages 处理镜像的主要逻辑
-func processImages(sourceImages []string, targetRepo string, verbose bool, dryRun bool) []ImageResult {
-	var results []ImageResult
-
-	if dryRun {
-		fmt.Println("DRY RUN MODE - No actual changes will be made")
-	}
-
-	for _, sourceImage := range sourceImages {
-		result := ImageResult{
-			SourceImage: sourceImage,
-			TargetImage: fmt.Sprintf("%s/%s", targetRepo, extractImageName(sourceImage)),
-			Success:     false,
+// buildAuthConfig 把配置文件中的 registries 段落和 --creds 命令行覆盖
+// 合并成 transfer 包使用的 AuthConfig。passwordFile 在这里读取一次，
+// 避免把密码长期保留在 Config 里。credsFlag 格式为 "username:password"，
+// 作为所有未在 registries 中列出的仓库的兜底凭据。
+func buildAuthConfig(registries map[string]RegistryAuth, credsFlag string) (transfer.AuthConfig, error) {
+	auth := make(transfer.AuthConfig, len(registries)+1)
+
+	for host, reg := range registries {
+		password := reg.Password
+		if reg.PasswordFile != "" {
+			data, err := os.ReadFile(reg.PasswordFile)
+			if err != nil {
+				return nil, fmt.Errorf("reading passwordFile for %s: %w", host, err)
+			}
+			password = strings.TrimSpace(string(data))
 		}
 
-		if verbose {
-			fmt.Printf("Processing image %s in detail:\n", sourceImage)
-			fmt.Printf("  Source: %s\n", sourceImage)
-			fmt.Printf("  Target: %s\n", result.TargetImage)
-		} else {
-			fmt.Println("Processing image:", sourceImage)
+		auth[host] = transfer.Auth{
+			Username:      reg.Username,
+			Password:      password,
+			IdentityToken: reg.IdentityToken,
+			TLSVerify:     reg.TLSVerify,
+			CAFile:        reg.CAFile,
 		}
+	}
 
-		if dryRun {
-			result.Success = true
-			results = append(results, result)
-			continue
+	if credsFlag != "" {
+		username, password, ok := strings.Cut(credsFlag, ":")
+		if !ok {
+			return nil, fmt.Errorf("--creds must be in the form USERNAME:PASSWORD")
 		}
+		auth["*"] = transfer.Auth{Username: username, Password: password}
+	}
 
-		// 拉取源镜像
-		err := retryOperation(func() error {
-			if verbose {
-				fmt.Printf("  Pulling source image: %s\n", sourceImage)
-			}
-			return executeCommand("docker", "pull", sourceImage)
-		}, "Pulling", verbose)
+	return auth, nil
+}
 
+// platformOptions 收集了与多架构平台选择相关的命令行参数。
+type platformOptions struct {
+	all        bool
+	platforms  []transfer.Platform
+	fromConfig bool
+}
+
+// selectionFor 为单个镜像计算出要传给 Backend.Push 的 PlatformSelection：
+// --platform-from-config 优先读取该镜像的 platform 字段，
+// 其次是全局的 --all-platforms / --platform。
+func (o platformOptions) selectionFor(spec ImageSpec) (transfer.PlatformSelection, error) {
+	if o.fromConfig && spec.Platform != "" {
+		p, err := transfer.ParsePlatform(spec.Platform)
 		if err != nil {
-			result.FailStage = "pull"
-			result.Error = err
-			results = append(results, result)
-			continue
+			return transfer.PlatformSelection{}, fmt.Errorf("image %s: %w", spec.Source, err)
 		}
+		return transfer.PlatformSelection{Platforms: []transfer.Platform{p}}, nil
+	}
+	return transfer.PlatformSelection{All: o.all, Platforms: o.platforms}, nil
+}
 
-		// 标记镜像
-		err = retryOperation(func() error {
-			if verbose {
-				fmt.Printf("  Tagging image as: %s\n", result.TargetImage)
-			}
-			return executeCommand("docker", "tag", sourceImage, result.TargetImage)
-		}, "Tagging", verbose)
+// signatureOptions 收集了与 cosign/sigstore 签名相关的命令行参数。
+type signatureOptions struct {
+	copySignatures bool
+	policy         *sigstore.Policy    // nil 表示未启用 --verify-signatures
+	auth           transfer.AuthConfig // 复用 --creds/registries 里配置的凭据去拉取签名制品
+}
 
-		if err != nil {
-			result.FailStage = "tag"
-			result.Error = err
-			results = append(results, result)
-			continue
-		}
+// processImages 处理镜像的主要逻辑。镜像之间通过一个大小为 parallel 的
+// worker pool 并发处理，但每个仓库（registry host）有独立的限流器，
+// 避免并发打爆单个仓库。结果按 images 的原始顺序收集，
+// 因此 printResults 看到的输出和串行执行时一致。
+func processImages(images []ImageSpec, defaultTarget string, backend transfer.Backend, platforms platformOptions, signatures signatureOptions, verbose bool, dryRun bool, parallel int) []ImageResult {
+	results := make([]ImageResult, len(images))
 
-		// 推送镜像到目标仓库
-		err = retryOperation(func() error {
-			if verbose {
-				fmt.Printf("  Pushing image to target repository: %s\n", result.TargetImage)
-			}
-			return executeCommand("docker", "push", result.TargetImage)
-		}, "Pushing", verbose)
+	if dryRun {
+		fmt.Println("DRY RUN MODE - No actual changes will be made")
+	}
 
-		if err != nil {
-			result.FailStage = "push"
+	limiter := ratelimit.NewRegistryLimiter(registryBurst, registryRatePerSecond)
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+
+	for i, spec := range images {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, spec ImageSpec) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = processImage(spec, defaultTarget, backend, platforms, signatures, limiter, verbose, dryRun)
+		}(i, spec)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// processImage 执行单个镜像的 pull/tag/push 流水线。
+func processImage(spec ImageSpec, defaultTarget string, backend transfer.Backend, platforms platformOptions, signatures signatureOptions, limiter *ratelimit.RegistryLimiter, verbose bool, dryRun bool) (result ImageResult) {
+	start := time.Now()
+	defer func() { result.Duration = time.Since(start) }()
+
+	sourceImage := spec.Source
+	result = ImageResult{
+		SourceImage: sourceImage,
+		TargetImage: buildTargetImage(spec, defaultTarget),
+		Success:     false,
+	}
+
+	if verbose {
+		fmt.Printf("Processing image %s in detail:\n", sourceImage)
+		fmt.Printf("  Source: %s\n", sourceImage)
+		fmt.Printf("  Target: %s\n", result.TargetImage)
+	} else {
+		fmt.Println("Processing image:", sourceImage)
+	}
+
+	if dryRun {
+		result.Success = true
+		return result
+	}
+
+	// 拉取源镜像
+	err := retryOperation(func() error {
+		limiter.Wait(transfer.RegistryHost(sourceImage))
+		return backend.Pull(sourceImage)
+	}, "Pulling", verbose)
+
+	if err != nil {
+		result.FailStage = "pull"
+		result.Error = err
+		return result
+	}
+
+	if digestBackend, ok := backend.(transfer.DigestBackend); ok {
+		if d, err := digestBackend.Digest(sourceImage); err == nil {
+			result.SourceDigest = d
+		}
+	}
+
+	// 按策略校验源镜像的 cosign/sigstore 签名，拒绝镜像到不受信任的内容。
+	// 用和 backend.Pull 相同的 registry 凭据去拉取签名制品，否则私有源仓库
+	// 的镜像即使拉取成功，也会在这一步因为匿名访问而校验失败。
+	if signatures.policy != nil {
+		sigAuth := signatures.auth.For(transfer.RegistryHost(sourceImage))
+		if err := sigstore.Verify(context.Background(), sourceImage, signatures.policy.For(sourceImage), sigAuth); err != nil {
+			result.FailStage = "verify"
 			result.Error = err
-			results = append(results, result)
-			continue
+			return result
 		}
+	}
 
-		result.Success = true
-		if verbose {
-			fmt.Printf("  Successfully processed image: %s\n", sourceImage)
+	// 标记镜像
+	err = retryOperation(func() error {
+		return backend.Tag(sourceImage, result.TargetImage)
+	}, "Tagging", verbose)
+
+	if err != nil {
+		result.FailStage = "tag"
+		result.Error = err
+		return result
+	}
+
+	// 推送镜像到目标仓库
+	sel, err := platforms.selectionFor(spec)
+	if err != nil {
+		result.FailStage = "push"
+		result.Error = err
+		return result
+	}
+
+	var pushResult transfer.PushResult
+	err = retryOperation(func() error {
+		limiter.Wait(transfer.RegistryHost(result.TargetImage))
+		var pushErr error
+		pushResult, pushErr = backend.Push(result.TargetImage, sel)
+		return pushErr
+	}, "Pushing", verbose)
+
+	result.Platforms = pushResult.Platforms
+	result.BytesTransferred = pushResult.BytesTransferred
+	result.TargetDigest = pushResult.TargetDigest
+
+	if err != nil {
+		result.FailStage = "push"
+		result.Error = err
+		return result
+	}
+
+	result.Success = true
+	if verbose {
+		fmt.Printf("  Successfully processed image: %s\n", sourceImage)
+	}
+
+	if signatures.copySignatures {
+		if err := copySignatureArtifacts(backend, sourceImage, result.TargetImage, limiter); err != nil && verbose {
+			fmt.Printf("  Warning: failed to copy signature for %s: %v\n", sourceImage, err)
 		}
+	}
 
-		results = append(results, result)
+	return result
+}
+
+// copySignatureArtifacts 把 source 镜像的 cosign/sigstore 签名制品
+// （遵循 sha256-<digest>.sig 标签约定）复制到 target 所在的仓库。
+// 签名缺失或后端不支持读取 digest 都不算整体传输失败，只是没有签名可带。
+func copySignatureArtifacts(backend transfer.Backend, source, target string, limiter *ratelimit.RegistryLimiter) error {
+	digestBackend, ok := backend.(transfer.DigestBackend)
+	if !ok {
+		return fmt.Errorf("backend does not support reading image digests; use --backend=containers-image")
 	}
 
-	return results
+	digest, err := digestBackend.Digest(source)
+	if err != nil {
+		return fmt.Errorf("reading digest: %w", err)
+	}
+	sigTag, err := sigstore.SignatureTag(digest)
+	if err != nil {
+		return err
+	}
+
+	sigSource := stripTag(source) + ":" + sigTag
+	sigTarget := stripTag(target) + ":" + sigTag
+
+	if err := backend.Pull(sigSource); err != nil {
+		return fmt.Errorf("pulling %s: %w", sigSource, err)
+	}
+	if err := backend.Tag(sigSource, sigTarget); err != nil {
+		return fmt.Errorf("tagging %s: %w", sigTarget, err)
+	}
+	limiter.Wait(transfer.RegistryHost(sigTarget))
+	if _, err := backend.Push(sigTarget, transfer.PlatformSelection{}); err != nil {
+		return fmt.Errorf("pushing %s: %w", sigTarget, err)
+	}
+	return nil
+}
+
+// stripTag 去掉镜像引用中的 tag 部分，只在最后一个 "/" 之后寻找 ":"，
+// 这样 "host:5000/repo:tag" 中用于端口号的冒号不会被误当成 tag 分隔符。
+func stripTag(ref string) string {
+	lastSlash := strings.LastIndex(ref, "/")
+	lastColon := strings.LastIndex(ref, ":")
+	if lastColon > lastSlash {
+		return ref[:lastColon]
+	}
+	return ref
+}
+
+// buildTargetImage 计算一个镜像的目标引用：仓库部分优先取 spec.Target，
+// 否则回退到配置文件顶层的 target（已经按 --target-transport 处理过）；
+// 如果指定了 targetTag，则替换源镜像原有的 tag（如果有的话）。
+//
+// 当目标是一个本地归档传输（oci:/docker-archive:/dir:，参见
+// transfer.IsArchiveReference）时，镜像名是作为该传输引用自身的 tag 部分
+// 拼接的（比如 "oci:/backup/layout:nginx-latest"），而不是像仓库地址那样
+// 拼成路径段，这样同一个 OCI layout 或 tar 包可以容纳多个镜像。
+func buildTargetImage(spec ImageSpec, defaultTarget string) string {
+	targetRepo := spec.Target
+	if targetRepo == "" {
+		targetRepo = defaultTarget
+	}
+
+	name := extractImageName(spec.Source)
+	if spec.TargetTag != "" {
+		if idx := strings.LastIndex(name, ":"); idx != -1 {
+			name = name[:idx]
+		}
+		name = fmt.Sprintf("%s:%s", name, spec.TargetTag)
+	}
+
+	if transfer.IsArchiveReference(targetRepo) {
+		return fmt.Sprintf("%s:%s", targetRepo, strings.ReplaceAll(name, ":", "-"))
+	}
+	return fmt.Sprintf("%s/%s", targetRepo, name)
 }
 
-// retryOperation 封装重试逻辑
+// retryOperation 封装重试逻辑，使用指数退避加抖动，
+// 避免一个仓库短暂故障时所有并发 worker 在同一时刻集中重试。
 func retryOperation(operation func() error, operationName string, verbose bool) error {
 	var lastErr error
 	for attempt := 1; attempt <= MaxRetries; attempt++ {
@@ -226,23 +585,28 @@ func retryOperation(operation func() error, operationName string, verbose bool)
 		} else {
 			lastErr = err
 			if attempt < MaxRetries {
+				delay := backoffDelay(attempt)
 				if verbose {
-					fmt.Printf("  %s failed (attempt %d/%d): %v. Retrying in %d seconds...\n",
-						operationName, attempt, MaxRetries, err, RetryInterval)
+					fmt.Printf("  %s failed (attempt %d/%d): %v. Retrying in %s...\n",
+						operationName, attempt, MaxRetries, err, delay)
 				}
-				time.Sleep(RetryInterval * time.Second)
+				time.Sleep(delay)
 			}
 		}
 	}
 	return lastErr
 }
 
-// executeCommand 执行命令行命令
-func executeCommand(command string, args ...string) error {
-	cmd := exec.Command(command, args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+// backoffDelay 计算第 attempt 次重试前应等待的时长：
+// base * factor^(attempt-1)，封顶 retryMaxDelay，再叠加 ±retryJitter 的随机抖动。
+func backoffDelay(attempt int) time.Duration {
+	delay := float64(retryBaseDelay) * math.Pow(retryFactor, float64(attempt-1))
+	if delay > float64(retryMaxDelay) {
+		delay = float64(retryMaxDelay)
+	}
+
+	jitter := 1 + retryJitter*(2*rand.Float64()-1)
+	return time.Duration(delay * jitter)
 }
 
 // extractImageName 从完整的镜像路径中提取镜像名和标签
@@ -272,6 +636,13 @@ func printResults(results []ImageResult, verbose bool) {
 		if result.Success {
 			successful++
 			fmt.Printf("✅ %s -> %s\n", result.SourceImage, result.TargetImage)
+			for _, p := range result.Platforms {
+				if p.Success {
+					fmt.Printf("   ✅ %s\n", p.Platform)
+				} else {
+					fmt.Printf("   ❌ %s: %v\n", p.Platform, p.Error)
+				}
+			}
 		}
 	}
 
@@ -294,3 +665,184 @@ func printResults(results []ImageResult, verbose bool) {
 	fmt.Printf("\nSuccessful: %d", successful)
 	fmt.Printf("\nFailed: %d\n", failed)
 }
+
+// anyFailed reports whether at least one image failed to transfer, including
+// images that reported overall Success but recorded a failed PlatformResult
+// (e.g. a multi-arch push that copied some platforms and skipped others).
+func anyFailed(results []ImageResult) bool {
+	for _, r := range results {
+		if !r.Success {
+			return true
+		}
+		if anyPlatformFailed(r.Platforms) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyPlatformFailed reports whether platforms contains at least one failed entry.
+func anyPlatformFailed(platforms []transfer.PlatformResult) bool {
+	for _, p := range platforms {
+		if !p.Success {
+			return true
+		}
+	}
+	return false
+}
+
+// failedPlatformMessages renders one "platform: error" line per failed
+// entry in platforms, for inclusion in the JUnit <failure> content.
+func failedPlatformMessages(platforms []transfer.PlatformResult) []string {
+	var messages []string
+	for _, p := range platforms {
+		if p.Success {
+			continue
+		}
+		if p.Error != nil {
+			messages = append(messages, fmt.Sprintf("%s: %s", p.Platform, p.Error))
+		} else {
+			messages = append(messages, p.Platform)
+		}
+	}
+	return messages
+}
+
+// jsonPlatformResult is the --output=json representation of a
+// transfer.PlatformResult: Error is flattened to a string (like
+// jsonImageResult.Error) since the Go error interface doesn't have a
+// sensible default JSON encoding, and the field names are camelCase to
+// match the rest of jsonImageResult.
+type jsonPlatformResult struct {
+	Platform string `json:"platform"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
+
+// toJSONPlatformResults maps transfer.PlatformResult into its JSON DTO.
+func toJSONPlatformResults(platforms []transfer.PlatformResult) []jsonPlatformResult {
+	if len(platforms) == 0 {
+		return nil
+	}
+	out := make([]jsonPlatformResult, len(platforms))
+	for i, p := range platforms {
+		out[i] = jsonPlatformResult{Platform: p.Platform, Success: p.Success}
+		if p.Error != nil {
+			out[i].Error = p.Error.Error()
+		}
+	}
+	return out
+}
+
+// jsonImageResult is the --output=json representation of an ImageResult.
+// error is flattened to a string since the Go error interface doesn't have
+// a sensible default JSON encoding, and omitempty keeps successful results
+// free of noise fields.
+type jsonImageResult struct {
+	SourceImage      string               `json:"sourceImage"`
+	TargetImage      string               `json:"targetImage"`
+	Success          bool                 `json:"success"`
+	FailStage        string               `json:"failStage,omitempty"`
+	Error            string               `json:"error,omitempty"`
+	DurationSeconds  float64              `json:"durationSeconds"`
+	BytesTransferred int64                `json:"bytesTransferred,omitempty"`
+	SourceDigest     string               `json:"sourceDigest,omitempty"`
+	TargetDigest     string               `json:"targetDigest,omitempty"`
+	Platforms        []jsonPlatformResult `json:"platforms,omitempty"`
+}
+
+// printResultsJSON writes results as a JSON array to stdout, for consumption
+// by CI pipelines and other tooling.
+func printResultsJSON(results []ImageResult) error {
+	out := make([]jsonImageResult, len(results))
+	for i, r := range results {
+		out[i] = jsonImageResult{
+			SourceImage:      r.SourceImage,
+			TargetImage:      r.TargetImage,
+			Success:          r.Success,
+			FailStage:        r.FailStage,
+			DurationSeconds:  r.Duration.Seconds(),
+			BytesTransferred: r.BytesTransferred,
+			SourceDigest:     r.SourceDigest,
+			TargetDigest:     r.TargetDigest,
+			Platforms:        toJSONPlatformResults(r.Platforms),
+		}
+		if r.Error != nil {
+			out[i].Error = r.Error.Error()
+		}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// junitTestSuite/junitTestCase/junitFailure model just enough of the JUnit
+// XML schema for CI systems like GitLab/Jenkins to ingest --output=junit as
+// a test report: one <testsuite> with one <testcase> per image.
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// printResultsJUnit writes results as a JUnit XML report to stdout.
+func printResultsJUnit(results []ImageResult) error {
+	suite := junitTestSuite{
+		Name:      "image-trans-cli",
+		Tests:     len(results),
+		TestCases: make([]junitTestCase, len(results)),
+	}
+
+	for i, r := range results {
+		tc := junitTestCase{
+			Name:      fmt.Sprintf("%s -> %s", r.SourceImage, r.TargetImage),
+			Classname: "image-trans-cli",
+			Time:      r.Duration.Seconds(),
+		}
+		failedPlatforms := failedPlatformMessages(r.Platforms)
+		if !r.Success || len(failedPlatforms) > 0 {
+			suite.Failures++
+			message := fmt.Sprintf("failed at stage %q", r.FailStage)
+			if r.Success {
+				message = "partial failure: one or more platforms failed"
+			}
+			var content string
+			if r.Error != nil {
+				content = r.Error.Error()
+			}
+			for _, m := range failedPlatforms {
+				if content != "" {
+					content += "\n"
+				}
+				content += m
+			}
+			tc.Failure = &junitFailure{Message: message, Content: content}
+		}
+		suite.TestCases[i] = tc
+	}
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(xml.Header + string(data))
+	return nil
+}
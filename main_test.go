@@ -0,0 +1,53 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStripTag(t *testing.T) {
+	cases := []struct {
+		name string
+		ref  string
+		want string
+	}{
+		{"simple repo:tag", "repo:tag", "repo"},
+		{"no tag", "registry.io/repo", "registry.io/repo"},
+		{"host with port and tag", "host:5000/repo:tag", "host:5000/repo"},
+		{"host with port, no tag", "host:5000/repo", "host:5000/repo"},
+		{"oci archive reference", "oci:/backup/layout:nginx-latest", "oci:/backup/layout"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := stripTag(tc.ref); got != tc.want {
+				t.Errorf("stripTag(%q) = %q, want %q", tc.ref, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBackoffDelayBounds(t *testing.T) {
+	minJitter := 1 - retryJitter
+	maxJitter := 1 + retryJitter
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay := backoffDelay(attempt)
+		if delay <= 0 {
+			t.Fatalf("backoffDelay(%d) = %s, want > 0", attempt, delay)
+		}
+
+		// Every delay must stay within jitter range of the capped
+		// exponential value, regardless of how high attempt climbs.
+		base := float64(retryBaseDelay) * math.Pow(retryFactor, float64(attempt-1))
+		if base > float64(retryMaxDelay) {
+			base = float64(retryMaxDelay)
+		}
+		min := time.Duration(base * minJitter)
+		max := time.Duration(base * maxJitter)
+		if delay < min || delay > max {
+			t.Errorf("backoffDelay(%d) = %s, want in [%s, %s]", attempt, delay, min, max)
+		}
+	}
+}